@@ -0,0 +1,212 @@
+package bambootest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	bamboo "github.com/stefan-kiss/go-bamboo"
+)
+
+// FakeState holds the in-memory data served by the fake client returned
+// from NewFakeClient. Tests seed it directly before exercising the client.
+type FakeState struct {
+	mu sync.Mutex
+
+	Plans    []*bamboo.Plan
+	Results  map[string][]bamboo.Result
+	Comments map[string][]*bamboo.Comment
+}
+
+func newFakeState() *FakeState {
+	return &FakeState{
+		Results:  make(map[string][]bamboo.Result),
+		Comments: make(map[string][]*bamboo.Comment),
+	}
+}
+
+var (
+	fakeStatesMu sync.Mutex
+	fakeStates   = map[*bamboo.Client]*FakeState{}
+)
+
+// State returns the FakeState backing client, so a test can seed Plans,
+// Results and Comments before calling into the client. Panics if client
+// wasn't created by NewFakeClient.
+func State(client *bamboo.Client) *FakeState {
+	fakeStatesMu.Lock()
+	defer fakeStatesMu.Unlock()
+
+	state, ok := fakeStates[client]
+	if !ok {
+		panic("bambootest: client was not created by NewFakeClient")
+	}
+	return state
+}
+
+// NewFakeClient returns a *bamboo.Client backed by an in-memory fake
+// Bamboo server, so callers can exercise client.Plans.List(),
+// client.Results.GetExpanded(...), client.Comments.AddComment(...) etc.
+// without running a real Bamboo server. Seed/inspect its data via State.
+func NewFakeClient(t *testing.T) *bamboo.Client {
+	t.Helper()
+
+	state := newFakeState()
+	server := httptest.NewServer(state.handler())
+	t.Cleanup(server.Close)
+
+	client := bamboo.NewSimpleClient(nil, "", "")
+	client.SetURL(server.URL)
+
+	fakeStatesMu.Lock()
+	fakeStates[client] = state
+	fakeStatesMu.Unlock()
+	t.Cleanup(func() {
+		fakeStatesMu.Lock()
+		delete(fakeStates, client)
+		fakeStatesMu.Unlock()
+	})
+
+	return client
+}
+
+func (s *FakeState) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/latest/plan.json", s.handlePlans)
+	mux.HandleFunc("/rest/api/latest/plan/", s.handlePlan)
+	mux.HandleFunc("/rest/api/latest/result/", s.handleResult)
+	return mux
+}
+
+// handlePlans serves plan.json, honoring the start-index and max-results
+// query parameters; Size always reports the total number of seeded plans
+func (s *FakeState) handlePlans(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plans := s.Plans
+	q := r.URL.Query()
+	if start, err := strconv.Atoi(q.Get("start-index")); err == nil && start > 0 {
+		if start > len(plans) {
+			start = len(plans)
+		}
+		plans = plans[start:]
+	}
+	if max, err := strconv.Atoi(q.Get("max-results")); err == nil && max >= 0 && max < len(plans) {
+		plans = plans[:max]
+	}
+
+	writeJSON(w, http.StatusOK, &bamboo.PlanResponse{
+		ResourceMetadata: &bamboo.ResourceMetadata{},
+		Plans: &bamboo.Plans{
+			CollectionMetadata: &bamboo.CollectionMetadata{Size: len(s.Plans)},
+			PlanList:           plans,
+		},
+	})
+}
+
+// handlePlan serves plan/{key}, plan/{key}/branch/{branch}.json and
+// plan/{key}/enable
+func (s *FakeState) handlePlan(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/rest/api/latest/plan/")
+
+	switch {
+	case strings.Contains(path, "/branch/") && r.Method == http.MethodPut:
+		w.WriteHeader(http.StatusOK)
+	case strings.HasSuffix(path, "/enable") && r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusOK)
+	default:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		planKey := path
+		for _, plan := range s.Plans {
+			if plan.Key == planKey {
+				writeJSON(w, http.StatusOK, plan)
+				return
+			}
+		}
+		http.Error(w, fmt.Sprintf("plan %q not found", planKey), http.StatusNotFound)
+	}
+}
+
+// handleResult serves result/{key}.json (list), result/{key}-latest.json
+// and result/{key}-{buildNumber}.json (single result), and
+// result/{key}/comment.json. Which shape to serve is decided by the
+// request path itself, never by how many results happen to be seeded
+// under a key: a bare plan key is always a list, "-latest" or a build
+// number suffix is always a single Result.
+func (s *FakeState) handleResult(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/rest/api/latest/result/"), ".json")
+
+	if strings.HasSuffix(path, "/comment") {
+		s.handleComment(w, r, strings.TrimSuffix(path, "/comment"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if planKey := strings.TrimSuffix(path, "-latest"); planKey != path {
+		results := s.Results[planKey]
+		if len(results) == 0 {
+			http.Error(w, fmt.Sprintf("no results for %q", planKey), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, &results[len(results)-1])
+		return
+	}
+
+	if results, ok := s.Results[path]; ok {
+		writeJSON(w, http.StatusOK, &bamboo.ResultsResponse{
+			ResourceMetadata: &bamboo.ResourceMetadata{},
+			Results: &bamboo.Results{
+				CollectionMetadata: bamboo.CollectionMetadata{Size: len(results)},
+				Result:             results,
+			},
+		})
+		return
+	}
+
+	for planKey, results := range s.Results {
+		prefix := planKey + "-"
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(path, prefix)); err == nil {
+			for i := range results {
+				if results[i].BuildNumber == n || results[i].Number == n {
+					writeJSON(w, http.StatusOK, &results[i])
+					return
+				}
+			}
+		}
+	}
+
+	http.Error(w, fmt.Sprintf("no results for %q", path), http.StatusNotFound)
+}
+
+func (s *FakeState) handleComment(w http.ResponseWriter, r *http.Request, resultKey string) {
+	comment := &bamboo.Comment{}
+	if err := json.NewDecoder(r.Body).Decode(comment); err != nil {
+		http.Error(w, fmt.Sprintf("decoding comment: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.Comments[resultKey] = append(s.Comments[resultKey], comment)
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
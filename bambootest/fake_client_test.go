@@ -0,0 +1,54 @@
+package bambootest
+
+import (
+	"testing"
+
+	bamboo "github.com/stefan-kiss/go-bamboo"
+)
+
+func TestFakeClientServesSeededPlans(t *testing.T) {
+	client := NewFakeClient(t)
+	state := State(client)
+	state.Plans = []*bamboo.Plan{{Key: "PROJ-PLAN"}}
+
+	plans, _, err := client.Plans.List()
+	if err != nil {
+		t.Fatalf("List: %s", err)
+	}
+	if len(plans) != 1 || plans[0].Key != "PROJ-PLAN" {
+		t.Errorf("got %+v, want one plan keyed PROJ-PLAN", plans)
+	}
+}
+
+func TestFakeClientRoutesSingleResultByPathShape(t *testing.T) {
+	client := NewFakeClient(t)
+	state := State(client)
+	state.Results = map[string][]bamboo.Result{
+		"PROJ-PLAN": {{BuildNumber: 1}},
+	}
+
+	latest, _, err := client.Results.LatestResult("PROJ-PLAN")
+	if err != nil {
+		t.Fatalf("LatestResult: %s", err)
+	}
+	if latest.BuildNumber != 1 {
+		t.Errorf("got build number %d, want 1", latest.BuildNumber)
+	}
+
+	list, _, err := client.Results.ListResults("PROJ-PLAN")
+	if err != nil {
+		t.Fatalf("ListResults: %s", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("got %d results, want 1", len(list))
+	}
+}
+
+func TestStatePanicsForUnknownClient(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected State to panic for a client not created by NewFakeClient")
+		}
+	}()
+	State(bamboo.NewSimpleClient(nil, "", ""))
+}
@@ -0,0 +1,147 @@
+// Package bambootest provides test doubles for users of the bamboo
+// package: a record/replay http.RoundTripper pair for fixture-based
+// testing, and an in-memory fake Bamboo server.
+package bambootest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// fixture is a single recorded request/response pair, persisted as JSON
+type fixture struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"requestBody,omitempty"`
+	StatusCode  int         `json:"statusCode"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body"`
+}
+
+// RecordingTransport wraps Transport and records every request/response
+// pair it sees as a JSON fixture file under Dir, keyed by a hash of the
+// method, URL and request body
+type RecordingTransport struct {
+	// Transport is the underlying RoundTripper used to make the real
+	// call. Defaults to http.DefaultTransport when nil.
+	Transport http.RoundTripper
+	// Dir is the directory fixtures are written to
+	Dir string
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("bambootest: reading request body: %w", err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("bambootest: reading response body: %w", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	f := &fixture{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		RequestBody: string(reqBody),
+		StatusCode:  resp.StatusCode,
+		Header:      resp.Header,
+		Body:        string(respBody),
+	}
+
+	if err := writeFixture(t.Dir, f); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ReplayTransport serves fixtures previously written by RecordingTransport
+// back deterministically, without making any real network calls
+type ReplayTransport struct {
+	// Dir is the directory fixtures are read from
+	Dir string
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("bambootest: reading request body: %w", err)
+		}
+	}
+
+	f, err := readFixture(t.Dir, req.Method, req.URL.String(), string(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("bambootest: no recorded fixture for %s %s: %w", req.Method, req.URL, err)
+	}
+
+	return &http.Response{
+		StatusCode: f.StatusCode,
+		Status:     http.StatusText(f.StatusCode),
+		Header:     f.Header,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(f.Body))),
+		Request:    req,
+	}, nil
+}
+
+func fixtureKey(method, url, body string) string {
+	sum := sha256.Sum256([]byte(method + "\x00" + url + "\x00" + body))
+	return hex.EncodeToString(sum[:])
+}
+
+func fixturePath(dir, method, url, body string) string {
+	return filepath.Join(dir, fixtureKey(method, url, body)+".json")
+}
+
+func writeFixture(dir string, f *fixture) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fixturePath(dir, f.Method, f.URL, f.RequestBody), data, 0o644)
+}
+
+func readFixture(dir, method, url, body string) (*fixture, error) {
+	data, err := ioutil.ReadFile(fixturePath(dir, method, url, body))
+	if err != nil {
+		return nil, err
+	}
+
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
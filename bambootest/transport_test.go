@@ -0,0 +1,54 @@
+package bambootest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecordAndReplayRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"hello":"world"}`))
+	}))
+	defer upstream.Close()
+
+	recordClient := &http.Client{Transport: &RecordingTransport{Dir: dir}}
+	resp, err := recordClient.Get(upstream.URL + "/plan.json")
+	if err != nil {
+		t.Fatalf("recording request: %s", err)
+	}
+	recordedBody, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	replayClient := &http.Client{Transport: &ReplayTransport{Dir: dir}}
+	replayResp, err := replayClient.Get(upstream.URL + "/plan.json")
+	if err != nil {
+		t.Fatalf("replaying request: %s", err)
+	}
+	defer replayResp.Body.Close()
+
+	replayedBody, err := ioutil.ReadAll(replayResp.Body)
+	if err != nil {
+		t.Fatalf("reading replayed body: %s", err)
+	}
+
+	if string(replayedBody) != string(recordedBody) {
+		t.Errorf("replayed body %q, want %q", replayedBody, recordedBody)
+	}
+	if replayResp.StatusCode != http.StatusOK {
+		t.Errorf("replayed status %d, want 200", replayResp.StatusCode)
+	}
+}
+
+func TestReplayUnknownFixtureErrors(t *testing.T) {
+	client := &http.Client{Transport: &ReplayTransport{Dir: t.TempDir()}}
+
+	if _, err := client.Get("http://example.invalid/never-recorded.json"); err == nil {
+		t.Fatal("expected an error for a request with no recorded fixture")
+	}
+}
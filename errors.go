@@ -0,0 +1,148 @@
+package bamboo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// APIError is returned by service methods when the Bamboo REST API
+// responds with a non-success status code. Unlike simpleError, it carries
+// enough context for a caller to handle specific failure modes without
+// string-matching err.Error() — compare with errors.Is(err, ErrNotFound)
+// and friends, or inspect StatusCode/Response directly.
+type APIError struct {
+	StatusCode    int
+	Endpoint      string
+	Method        string
+	BambooMessage string
+	Response      *http.Response
+}
+
+func (e *APIError) Error() string {
+	if e.BambooMessage != "" {
+		return fmt.Sprintf("bamboo: %s %s: %d %s", e.Method, e.Endpoint, e.StatusCode, e.BambooMessage)
+	}
+	return fmt.Sprintf("bamboo: %s %s: %d", e.Method, e.Endpoint, e.StatusCode)
+}
+
+// Is lets errors.Is(err, ErrNotFound) (and friends) match any APIError
+// carrying the corresponding status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+// Sentinel errors matched via APIError.Is. Compare with errors.Is, e.g.
+// errors.Is(err, bamboo.ErrNotFound), rather than string-matching err.
+var (
+	ErrNotFound     = errors.New("bamboo: not found")
+	ErrUnauthorized = errors.New("bamboo: unauthorized")
+	ErrConflict     = errors.New("bamboo: conflict")
+)
+
+// IsNotFound reports whether err is an APIError for a 404 response
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized reports whether err is an APIError for a 401 response
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsConflict reports whether err is an APIError for a 409 response
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// newAPIError builds an APIError from response, parsing Bamboo's JSON
+// error envelope ({"message": "..."}) into BambooMessage when response.Body
+// is still readable. Call sites that decode a response body themselves must
+// go through doJSON rather than Client.Do directly, since Client.Do consumes
+// and closes the body while decoding — doJSON buffers the body once and
+// leaves a fresh, unread copy on response.Body so newAPIError can still
+// parse the envelope after a failed or skipped decode.
+func newAPIError(method, endpoint string, response *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: response.StatusCode,
+		Endpoint:   endpoint,
+		Method:     method,
+		Response:   response,
+	}
+
+	var envelope struct {
+		Message string `json:"message"`
+	}
+	if response.Body != nil {
+		if err := json.NewDecoder(response.Body).Decode(&envelope); err == nil {
+			apiErr.BambooMessage = envelope.Message
+		}
+	}
+
+	return apiErr
+}
+
+// doJSON sends request via client.RawDo and decodes a successful JSON
+// response into v itself, instead of going through Client.Do. Client.Do
+// consumes and closes the response body as part of decoding into v, which
+// leaves nothing for newAPIError to read back out on a non-success status;
+// RawDo does not touch the body (RawService.GetRaw already relies on that
+// to read raw responses), so decoding here ourselves gives every caller
+// both a populated v on success and an intact body for newAPIError on
+// failure. response.Body is always replaced with a fresh, unread reader
+// over the buffered bytes before returning.
+func doJSON(client *Client, request *http.Request, v interface{}) (*http.Response, error) {
+	response, err := client.RawDo(request, nil)
+	if err != nil {
+		return response, err
+	}
+	if response.Body == nil {
+		return response, nil
+	}
+
+	body, readErr := io.ReadAll(response.Body)
+	response.Body.Close()
+	if readErr != nil {
+		return response, fmt.Errorf("reading response body: %w", readErr)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	if v == nil || len(body) == 0 {
+		return response, nil
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response, nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return response, fmt.Errorf("decoding response body: %w", err)
+	}
+
+	return response, nil
+}
+
+// simpleError is the unstructured error type this package used before
+// APIError existed.
+//
+// Deprecated: use *APIError instead, which carries the status code and
+// endpoint so callers don't have to string-match err.Error(). simpleError
+// is kept around for one release for compatibility, and for validation
+// errors caught before a request is ever sent (those have no status code
+// or response to report).
+type simpleError struct {
+	s string
+}
+
+func (e *simpleError) Error() string {
+	return e.s
+}
@@ -0,0 +1,235 @@
+package bamboo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// defaultPageSize is how many items are requested per page when iterating
+// a collection endpoint and the caller didn't specify one
+const defaultPageSize = 100
+
+// ListOptions controls pagination for the Iter methods
+type ListOptions struct {
+	// PageSize is the number of items requested per page. Defaults to
+	// defaultPageSize when <= 0.
+	PageSize int
+}
+
+func (o *ListOptions) pageSize() int {
+	if o == nil || o.PageSize <= 0 {
+		return defaultPageSize
+	}
+	return o.PageSize
+}
+
+// PlanIterator iterates over the plans on a Bamboo server, fetching pages
+// of PlanIterator.pageSize results at a time and honoring ctx cancellation
+// between pages
+type PlanIterator struct {
+	ctx       context.Context
+	service   *PlanService
+	pageSize  int
+	start     int
+	page      []*Plan
+	pos       int
+	exhausted bool
+	err       error
+	resp      *http.Response
+}
+
+// Iter returns a PlanIterator over all plans on the Bamboo server
+func (p *PlanService) Iter(ctx context.Context, opts *ListOptions) *PlanIterator {
+	return &PlanIterator{
+		ctx:      ctx,
+		service:  p,
+		pageSize: opts.pageSize(),
+		pos:      -1,
+	}
+}
+
+// Next advances the iterator, fetching the next page when the current one
+// is exhausted. It returns false when iteration is done or ctx is done.
+func (it *PlanIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.pos+1 < len(it.page) {
+		it.pos++
+		return true
+	}
+
+	if it.exhausted {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if err := it.fetchNextPage(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if len(it.page) == 0 {
+		it.exhausted = true
+		return false
+	}
+
+	it.pos = 0
+	return true
+}
+
+func (it *PlanIterator) fetchNextPage() error {
+	request, err := it.service.client.NewRequest(http.MethodGet, "plan.json", nil)
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(it.ctx)
+
+	q := request.URL.Query()
+	q.Set("max-results", strconv.Itoa(it.pageSize))
+	q.Set("start-index", strconv.Itoa(it.start))
+	request.URL.RawQuery = q.Encode()
+
+	planResp := PlanResponse{}
+	response, err := doJSON(it.service.client, request, &planResp)
+	it.resp = response
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != 200 {
+		return newAPIError(http.MethodGet, "plan.json", response)
+	}
+
+	it.page = planResp.Plans.PlanList
+	it.start += len(it.page)
+	if len(it.page) < it.pageSize || it.start >= planResp.Plans.Size {
+		it.exhausted = true
+	}
+	return nil
+}
+
+// Plan returns the plan at the iterator's current position
+func (it *PlanIterator) Plan() *Plan {
+	if it.pos < 0 || it.pos >= len(it.page) {
+		return nil
+	}
+	return it.page[it.pos]
+}
+
+// Err returns the first error encountered during iteration, if any
+func (it *PlanIterator) Err() error {
+	return it.err
+}
+
+// ResultIterator iterates over the results for a plan, fetching pages of
+// ResultIterator.pageSize results at a time and honoring ctx cancellation
+// between pages
+type ResultIterator struct {
+	ctx       context.Context
+	service   *ResultService
+	key       string
+	pageSize  int
+	start     int
+	page      []Result
+	pos       int
+	exhausted bool
+	err       error
+	resp      *http.Response
+}
+
+// Iter returns a ResultIterator over all results for the given plan key
+func (r *ResultService) Iter(ctx context.Context, key string, opts *ListOptions) *ResultIterator {
+	return &ResultIterator{
+		ctx:      ctx,
+		service:  r,
+		key:      key,
+		pageSize: opts.pageSize(),
+		pos:      -1,
+	}
+}
+
+// Next advances the iterator, fetching the next page when the current one
+// is exhausted. It returns false when iteration is done or ctx is done.
+func (it *ResultIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.pos+1 < len(it.page) {
+		it.pos++
+		return true
+	}
+
+	if it.exhausted {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if err := it.fetchNextPage(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if len(it.page) == 0 {
+		it.exhausted = true
+		return false
+	}
+
+	it.pos = 0
+	return true
+}
+
+func (it *ResultIterator) fetchNextPage() error {
+	request, err := it.service.client.NewRequest(http.MethodGet, listResultsURL(it.key), nil)
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(it.ctx)
+
+	q := request.URL.Query()
+	q.Set("max-results", strconv.Itoa(it.pageSize))
+	q.Set("start-index", strconv.Itoa(it.start))
+	request.URL.RawQuery = q.Encode()
+
+	result := ResultsResponse{}
+	response, err := doJSON(it.service.client, request, &result)
+	it.resp = response
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != 200 {
+		return newAPIError(http.MethodGet, listResultsURL(it.key), response)
+	}
+
+	it.page = result.Results.Result
+	it.start += len(it.page)
+	if len(it.page) < it.pageSize || it.start >= result.Results.Size {
+		it.exhausted = true
+	}
+	return nil
+}
+
+// Result returns the result at the iterator's current position
+func (it *ResultIterator) Result() *Result {
+	if it.pos < 0 || it.pos >= len(it.page) {
+		return nil
+	}
+	return &it.page[it.pos]
+}
+
+// Err returns the first error encountered during iteration, if any
+func (it *ResultIterator) Err() error {
+	return it.err
+}
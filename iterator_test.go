@@ -0,0 +1,50 @@
+package bamboo_test
+
+import (
+	"context"
+	"testing"
+
+	bamboo "github.com/stefan-kiss/go-bamboo"
+	"github.com/stefan-kiss/go-bamboo/bambootest"
+)
+
+func TestPlanIteratorPaginates(t *testing.T) {
+	client := bambootest.NewFakeClient(t)
+	state := bambootest.State(client)
+	state.Plans = []*bamboo.Plan{
+		{Key: "PROJ-ONE"},
+		{Key: "PROJ-TWO"},
+		{Key: "PROJ-THREE"},
+	}
+
+	it := client.Plans.Iter(context.Background(), &bamboo.ListOptions{PageSize: 1})
+
+	var keys []string
+	for it.Next() {
+		keys = append(keys, it.Plan().Key)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iterating plans: %s", err)
+	}
+
+	if len(keys) != 3 {
+		t.Fatalf("got %d plans, want 3: %v", len(keys), keys)
+	}
+}
+
+func TestPlanIteratorStopsOnCanceledContext(t *testing.T) {
+	client := bambootest.NewFakeClient(t)
+	state := bambootest.State(client)
+	state.Plans = []*bamboo.Plan{{Key: "PROJ-ONE"}, {Key: "PROJ-TWO"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	it := client.Plans.Iter(ctx, &bamboo.ListOptions{PageSize: 1})
+	if it.Next() {
+		t.Fatal("Next returned true on an already-canceled context")
+	}
+	if it.Err() == nil {
+		t.Error("expected Err to report the context cancellation")
+	}
+}
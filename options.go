@@ -0,0 +1,247 @@
+package bamboo
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BackoffFunc computes how long to wait before the given retry attempt
+// (0-indexed) is sent
+type BackoffFunc func(attempt int) time.Duration
+
+// ExponentialBackoff returns a BackoffFunc that doubles base on every
+// attempt and adds up to 50% jitter to avoid thundering-herd retries
+func ExponentialBackoff(base time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		d := base * time.Duration(1<<uint(attempt))
+		jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+		return d + jitter
+	}
+}
+
+// requestOptions collects the per-request behavior set via RequestOption
+type requestOptions struct {
+	ctx            context.Context
+	maxAttempts    int
+	backoff        BackoffFunc
+	rateLimiter    *RateLimiter
+	idempotencyKey string
+	headers        map[string]string
+}
+
+func defaultRequestOptions() *requestOptions {
+	return &requestOptions{maxAttempts: 1}
+}
+
+// RequestOption customizes how a single service method call is executed
+type RequestOption func(*requestOptions)
+
+// WithContext attaches ctx to the request, propagating deadlines and
+// cancellation down to the underlying http.Request
+func WithContext(ctx context.Context) RequestOption {
+	return func(o *requestOptions) { o.ctx = ctx }
+}
+
+// WithRetry retries the request up to maxAttempts times, sleeping according
+// to backoff between attempts, whenever the attempt failed with a transport
+// error or a transient 5xx/429 response and the request method is safe to
+// retry (GET/HEAD, or any method carrying an idempotency key set via
+// WithIdempotencyKey)
+func WithRetry(maxAttempts int, backoff BackoffFunc) RequestOption {
+	return func(o *requestOptions) {
+		o.maxAttempts = maxAttempts
+		o.backoff = backoff
+	}
+}
+
+// WithRateLimit throttles the request through limiter. Construct one
+// RateLimiter with NewRateLimiter per Client and pass it to every call
+// that should share its budget — the limiter, not the Client, is what's
+// shared across goroutines, so its lifetime is whatever the caller gives it.
+func WithRateLimit(limiter *RateLimiter) RequestOption {
+	return func(o *requestOptions) { o.rateLimiter = limiter }
+}
+
+// WithIdempotencyKey marks the request as safe to retry even for
+// non-idempotent methods like POST, and sends key in the Idempotency-Key
+// header so the Bamboo server (or a proxy in front of it) can de-duplicate
+func WithIdempotencyKey(key string) RequestOption {
+	return func(o *requestOptions) { o.idempotencyKey = key }
+}
+
+// WithHeader sets an additional header on the outgoing request
+func WithHeader(k, v string) RequestOption {
+	return func(o *requestOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[k] = v
+	}
+}
+
+// doWithOptions applies opts to request and runs it against client,
+// retrying transient failures per WithRetry and respecting WithRateLimit
+func doWithOptions(client *Client, request *http.Request, v interface{}, opts ...RequestOption) (*http.Response, error) {
+	return executeWithOptions(client, request, opts, func(req *http.Request) (*http.Response, error) {
+		return doJSON(client, req, v)
+	})
+}
+
+// doRawWithOptions is the RawService equivalent of doWithOptions, running
+// the request through client.RawDo and leaving the response body unread
+// instead of decoding it via doJSON
+func doRawWithOptions(client *Client, request *http.Request, opts ...RequestOption) (*http.Response, error) {
+	return executeWithOptions(client, request, opts, func(req *http.Request) (*http.Response, error) {
+		return client.RawDo(req, nil)
+	})
+}
+
+func executeWithOptions(client *Client, request *http.Request, opts []RequestOption, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	o := defaultRequestOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	prepareRequest(request, o)
+
+	ctx := o.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var response *http.Response
+	var err error
+	for attempt := 0; attempt < o.maxAttempts; attempt++ {
+		if attempt > 0 {
+			rewindBody(request)
+		}
+
+		if o.rateLimiter != nil {
+			if werr := o.rateLimiter.wait(ctx); werr != nil {
+				return response, werr
+			}
+		}
+
+		response, err = do(request)
+
+		if !shouldRetry(request.Method, response, err, o) || attempt == o.maxAttempts-1 {
+			return response, err
+		}
+
+		backoff := o.backoff
+		if backoff == nil {
+			backoff = ExponentialBackoff(100 * time.Millisecond)
+		}
+
+		select {
+		case <-time.After(backoff(attempt)):
+		case <-ctx.Done():
+			return response, ctx.Err()
+		}
+	}
+
+	return response, err
+}
+
+// contextFromOptions returns the context.Context carried by opts via
+// WithContext, or context.Background() if none was set. Iter-based methods
+// such as PlanService.List and ResultService.ListResults only honor
+// WithContext among opts, since their iterators' fetchNextPage doesn't go
+// through doWithOptions/executeWithOptions.
+func contextFromOptions(opts []RequestOption) context.Context {
+	o := defaultRequestOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.ctx != nil {
+		return o.ctx
+	}
+	return context.Background()
+}
+
+func prepareRequest(request *http.Request, o *requestOptions) {
+	if o.ctx != nil {
+		*request = *request.WithContext(o.ctx)
+	}
+	for k, v := range o.headers {
+		request.Header.Set(k, v)
+	}
+	if o.idempotencyKey != "" {
+		request.Header.Set("Idempotency-Key", o.idempotencyKey)
+	}
+}
+
+// rewindBody resets request.Body to its original contents before a retry,
+// since the previous attempt may have already drained it
+func rewindBody(request *http.Request) {
+	if request.GetBody == nil {
+		return
+	}
+	if body, err := request.GetBody(); err == nil {
+		request.Body = body
+	}
+}
+
+func shouldRetry(method string, response *http.Response, err error, o *requestOptions) bool {
+	if o.maxAttempts <= 1 {
+		return false
+	}
+	if err != nil {
+		return isIdempotentMethod(method) || o.idempotencyKey != ""
+	}
+	if response == nil {
+		return false
+	}
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500 {
+		return isIdempotentMethod(method) || o.idempotencyKey != ""
+	}
+	return false
+}
+
+func isIdempotentMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// RateLimiter is a token-bucket rate limiter that can be shared across
+// goroutines and across every call made against a single Client: create
+// one with NewRateLimiter and pass it to WithRateLimit on each call that
+// should draw from the same budget.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rps    float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing rps requests per second
+func NewRateLimiter(rps float64) *RateLimiter {
+	return &RateLimiter{rps: rps, tokens: rps, last: time.Now()}
+}
+
+func (b *RateLimiter) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.rps, b.tokens+now.Sub(b.last).Seconds()*b.rps)
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((1 - b.tokens) / b.rps * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
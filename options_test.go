@@ -0,0 +1,75 @@
+package bamboo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	bamboo "github.com/stefan-kiss/go-bamboo"
+)
+
+func TestGetNumberRetriesOn503(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"plans":{"size":1}}`))
+	}))
+	defer ts.Close()
+
+	client := bamboo.NewSimpleClient(nil, "", "")
+	client.SetURL(ts.URL)
+
+	n, _, err := client.Plans.GetNumber(bamboo.WithRetry(3, bamboo.ExponentialBackoff(time.Millisecond)))
+	if err != nil {
+		t.Fatalf("GetNumber: %s", err)
+	}
+	if n != 1 {
+		t.Errorf("got %d plans, want 1", n)
+	}
+	if attempts != 3 {
+		t.Errorf("got %d attempts, want 3", attempts)
+	}
+}
+
+func TestGetNumberWithoutRetryFailsOn503(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := bamboo.NewSimpleClient(nil, "", "")
+	client.SetURL(ts.URL)
+
+	if _, _, err := client.Plans.GetNumber(); err == nil {
+		t.Fatal("expected an error for a 503 response without WithRetry")
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1 (no retry configured)", attempts)
+	}
+}
+
+func TestRequestsSucceedThroughSharedRateLimiter(t *testing.T) {
+	limiter := bamboo.NewRateLimiter(100)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"plans":{"size":0}}`))
+	}))
+	defer ts.Close()
+
+	client := bamboo.NewSimpleClient(nil, "", "")
+	client.SetURL(ts.URL)
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := client.Plans.GetNumber(bamboo.WithRateLimit(limiter)); err != nil {
+			t.Fatalf("GetNumber: %s", err)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package bamboo
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
+	"sort"
+	"strings"
 )
 
 // PlanService handles communication with the plan related methods
@@ -61,8 +63,46 @@ type PlanVariable struct {
 	IsPassword   bool   `json:"isPassword"`
 }
 
+// String returns the variable in "key=value" form, redacting Value when
+// IsPassword is set so password-typed variables are safe to log
+func (v PlanVariable) String() string {
+	value := v.Value
+	if v.IsPassword {
+		value = "********"
+	}
+	return fmt.Sprintf("%s=%s", v.Key, value)
+}
+
+// planVariableWire is the JSON wire shape of a PlanVariable, without
+// PlanVariable's redacting MarshalJSON. SetVar marshals its request body
+// through planVariableWire so the real Value still reaches the Bamboo
+// server.
+type planVariableWire struct {
+	Key          string `json:"key"`
+	Value        string `json:"value"`
+	VariableType string `json:"variableType"`
+	IsPassword   bool   `json:"isPassword"`
+}
+
+// MarshalJSON redacts Value to "********" when IsPassword is set, so
+// json.Marshal(planVariable) is as safe to log as String() is. Code that
+// needs the real value on the wire, such as SetVar, marshals a
+// planVariableWire instead.
+func (v PlanVariable) MarshalJSON() ([]byte, error) {
+	value := v.Value
+	if v.IsPassword {
+		value = "********"
+	}
+	return json.Marshal(planVariableWire{
+		Key:          v.Key,
+		Value:        value,
+		VariableType: v.VariableType,
+		IsPassword:   v.IsPassword,
+	})
+}
+
 // CreateBranch will create a plan branch with the given branch name for the specified build
-func (p *PlanService) CreateBranch(planKey, branchName string, options *PlanCreateBranchOptions) (bool, *http.Response, error) {
+func (p *PlanService) CreateBranch(planKey, branchName string, options *PlanCreateBranchOptions, opts ...RequestOption) (bool, *http.Response, error) {
 	var u string
 	if !emptyStrings(planKey, branchName) {
 		u = fmt.Sprintf("plan/%s/branch/%s.json", planKey, branchName)
@@ -81,20 +121,20 @@ func (p *PlanService) CreateBranch(planKey, branchName string, options *PlanCrea
 		request.URL.RawQuery = values.Encode()
 	}
 
-	response, err := p.client.Do(request, nil)
+	response, err := doWithOptions(p.client, request, nil, opts...)
 	if err != nil {
 		return false, response, err
 	}
 
 	if !(response.StatusCode == 200) {
-		return false, response, &simpleError{fmt.Sprintf("Create returned %d", response.StatusCode)}
+		return false, response, newAPIError(http.MethodPut, u, response)
 	}
 
 	return true, response, nil
 }
 
 // GetNumber returns the number of plans on the Bamboo server
-func (p *PlanService) GetNumber() (int, *http.Response, error) {
+func (p *PlanService) GetNumber(opts ...RequestOption) (int, *http.Response, error) {
 	request, err := p.client.NewRequest(http.MethodGet, "plan.json", nil)
 	if err != nil {
 		return 0, nil, err
@@ -106,51 +146,34 @@ func (p *PlanService) GetNumber() (int, *http.Response, error) {
 	request.URL.RawQuery = values.Encode()
 
 	planResp := PlanResponse{}
-	response, err := p.client.Do(request, &planResp)
+	response, err := doWithOptions(p.client, request, &planResp, opts...)
 	if err != nil {
 		return 0, response, err
 	}
 
 	if response.StatusCode != 200 {
-		return 0, response, &simpleError{fmt.Sprintf("Getting the number of plans returned %s", response.Status)}
+		return 0, response, newAPIError(http.MethodGet, "plan.json", response)
 	}
 
 	return planResp.Plans.Size, response, nil
 }
 
-// List gets information on all plans
-func (p *PlanService) List() ([]*Plan, *http.Response, error) {
-	// Get number of plans to set max-results
-	numPlans, resp, err := p.GetNumber()
-	if err != nil {
-		return nil, resp, err
-	}
+// List gets information on all plans. It is a thin wrapper around Iter
+// kept for backward compatibility; prefer Iter for large Bamboo servers.
+func (p *PlanService) List(opts ...RequestOption) ([]*Plan, *http.Response, error) {
+	it := p.Iter(contextFromOptions(opts), nil)
 
-	request, err := p.client.NewRequest(http.MethodGet, "plan.json", nil)
-	if err != nil {
-		return nil, nil, err
+	var plans []*Plan
+	for it.Next() {
+		plans = append(plans, it.Plan())
 	}
 
-	q := request.URL.Query()
-	q.Add("max-results", strconv.Itoa(numPlans))
-	request.URL.RawQuery = q.Encode()
-
-	planResp := PlanResponse{}
-	response, err := p.client.Do(request, &planResp)
-	if err != nil {
-		return nil, response, err
-	}
-
-	if response.StatusCode != 200 {
-		return nil, response, &simpleError{fmt.Sprintf("Getting plan information returned %s", response.Status)}
-	}
-
-	return planResp.Plans.PlanList, response, nil
+	return plans, it.resp, it.Err()
 }
 
 // ListKeys get all the plan keys for all build plans on Bamboo
-func (p *PlanService) ListKeys() ([]string, *http.Response, error) {
-	plans, response, err := p.List()
+func (p *PlanService) ListKeys(opts ...RequestOption) ([]string, *http.Response, error) {
+	plans, response, err := p.List(opts...)
 	if err != nil {
 		return nil, response, err
 	}
@@ -163,8 +186,8 @@ func (p *PlanService) ListKeys() ([]string, *http.Response, error) {
 }
 
 // ListNames returns a list of ShortNames of all plans
-func (p *PlanService) ListNames() ([]string, *http.Response, error) {
-	plans, response, err := p.List()
+func (p *PlanService) ListNames(opts ...RequestOption) ([]string, *http.Response, error) {
+	plans, response, err := p.List(opts...)
 	if err != nil {
 		return nil, response, err
 	}
@@ -177,8 +200,8 @@ func (p *PlanService) ListNames() ([]string, *http.Response, error) {
 }
 
 // NamesMap returns a map[string]string where the PlanKey is the key and the ShortName is the value
-func (p *PlanService) NamesMap() (map[string]string, *http.Response, error) {
-	plans, response, err := p.List()
+func (p *PlanService) NamesMap(opts ...RequestOption) (map[string]string, *http.Response, error) {
+	plans, response, err := p.List(opts...)
 	if err != nil {
 		return nil, response, err
 	}
@@ -192,14 +215,14 @@ func (p *PlanService) NamesMap() (map[string]string, *http.Response, error) {
 }
 
 // Disable will disable a plan or plan branch
-func (p *PlanService) Disable(planKey string) (*http.Response, error) {
+func (p *PlanService) Disable(planKey string, opts ...RequestOption) (*http.Response, error) {
 	u := fmt.Sprintf("plan/%s/enable", planKey)
 	request, err := p.client.NewRequest(http.MethodDelete, u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := p.client.Do(request, nil)
+	response, err := doWithOptions(p.client, request, nil, opts...)
 	if err != nil {
 		return response, err
 	}
@@ -207,7 +230,7 @@ func (p *PlanService) Disable(planKey string) (*http.Response, error) {
 }
 
 // GetVars will return a plan's variables
-func (p *PlanService) GetVars(planKey string) (VariableList, *http.Response, error) {
+func (p *PlanService) GetVars(planKey string, opts ...RequestOption) (VariableList, *http.Response, error) {
 	planResp := Plan{}
 
 	u := fmt.Sprintf("plan/%s", planKey)
@@ -220,7 +243,7 @@ func (p *PlanService) GetVars(planKey string) (VariableList, *http.Response, err
 	q.Add("expand", "variableContext")
 	request.URL.RawQuery = q.Encode()
 
-	response, err := p.client.Do(request, &planResp)
+	response, err := doWithOptions(p.client, request, &planResp, opts...)
 	if err != nil {
 		return nil, response, err
 	}
@@ -234,6 +257,98 @@ func (p *PlanService) GetVars(planKey string) (VariableList, *http.Response, err
 	return planResp.VariableContext.Variable, response, nil
 }
 
+// SetVar sets a single plan variable, creating it if it doesn't already exist
+func (p *PlanService) SetVar(planKey, name, value string, opts ...RequestOption) error {
+	u := fmt.Sprintf("plan/%s/variable/%s", planKey, name)
+	request, err := p.client.NewRequest(http.MethodPut, u, &planVariableWire{Key: name, Value: value})
+	if err != nil {
+		return err
+	}
+
+	response, err := doWithOptions(p.client, request, nil, opts...)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != 200 && response.StatusCode != 204 {
+		return newAPIError(http.MethodPut, u, response)
+	}
+
+	return nil
+}
+
+// DeleteVar removes a single plan variable
+func (p *PlanService) DeleteVar(planKey, name string, opts ...RequestOption) error {
+	u := fmt.Sprintf("plan/%s/variable/%s", planKey, name)
+	request, err := p.client.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := doWithOptions(p.client, request, nil, opts...)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != 200 && response.StatusCode != 204 {
+		return newAPIError(http.MethodDelete, u, response)
+	}
+
+	return nil
+}
+
+// BulkSetVarsError lists the variables BulkSetVars failed to set or delete,
+// keyed by variable name, along with the error encountered for each
+type BulkSetVarsError struct {
+	Failed map[string]error
+}
+
+func (e *BulkSetVarsError) Error() string {
+	names := make([]string, 0, len(e.Failed))
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("bamboo: failed to set variables: %s", strings.Join(names, ", "))
+}
+
+// BulkSetVars diffs vars against planKey's current variables and issues the
+// minimal set of SetVar/DeleteVar calls needed to make them match: existing
+// variables missing from vars are deleted, new or changed ones are set,
+// and unchanged ones are left alone. If any call fails, the rest are still
+// attempted and a *BulkSetVarsError lists every variable that failed.
+func (p *PlanService) BulkSetVars(planKey string, vars map[string]string, opts ...RequestOption) error {
+	current, _, err := p.GetVars(planKey, opts...)
+	if err != nil {
+		return err
+	}
+
+	failed := make(map[string]error)
+
+	for name, value := range vars {
+		if existing, err := current.GetVarValueE(name); err == nil && existing == value {
+			continue
+		}
+		if err := p.SetVar(planKey, name, value, opts...); err != nil {
+			failed[name] = err
+		}
+	}
+
+	for _, v := range current {
+		if _, wanted := vars[v.Key]; !wanted {
+			if err := p.DeleteVar(planKey, v.Key, opts...); err != nil {
+				failed[v.Key] = err
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return &BulkSetVarsError{Failed: failed}
+	}
+
+	return nil
+}
+
 // GetVarValueE returns the variable value or error if it's not found
 func (vl VariableList) GetVarValueE(name string) (string, error) {
 	for _, v := range vl {
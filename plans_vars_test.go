@@ -0,0 +1,95 @@
+package bamboo_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bamboo "github.com/stefan-kiss/go-bamboo"
+)
+
+func TestPlanVariableMarshalJSONRedactsPassword(t *testing.T) {
+	v := bamboo.PlanVariable{Key: "db.password", Value: "hunter2", IsPassword: true}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshaling: %s", err)
+	}
+
+	var decoded struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("unmarshaling: %s", err)
+	}
+	if decoded.Value != "********" {
+		t.Errorf("got value %q, want it redacted", decoded.Value)
+	}
+}
+
+// bulkVarsStub serves GetVars for "PROJ-PLAN" with three existing variables
+// and records every SetVar/DeleteVar call BulkSetVars makes against it, so
+// the Value each SetVar call sends on the wire can be verified.
+type bulkVarsStub struct {
+	setCalls    map[string]string
+	deleteCalls []string
+}
+
+func (s *bulkVarsStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/latest/plan/PROJ-PLAN":
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"variableContext":{"size":3,"max-results":3,"variable":[
+			{"key":"keep","value":"same"},
+			{"key":"changed","value":"old"},
+			{"key":"remove-me","value":"gone"}
+		]}}`)
+	case r.Method == http.MethodPut:
+		name := r.URL.Path[len("/rest/api/latest/plan/PROJ-PLAN/variable/"):]
+		var v struct {
+			Value string `json:"value"`
+		}
+		json.NewDecoder(r.Body).Decode(&v)
+		s.setCalls[name] = v.Value
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		name := r.URL.Path[len("/rest/api/latest/plan/PROJ-PLAN/variable/"):]
+		s.deleteCalls = append(s.deleteCalls, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unexpected request", http.StatusNotFound)
+	}
+}
+
+func TestBulkSetVarsAddsChangesAndRemoves(t *testing.T) {
+	stub := &bulkVarsStub{setCalls: map[string]string{}}
+	ts := httptest.NewServer(stub)
+	defer ts.Close()
+
+	client := bamboo.NewSimpleClient(nil, "", "")
+	client.SetURL(ts.URL)
+
+	err := client.Plans.BulkSetVars("PROJ-PLAN", map[string]string{
+		"keep":    "same",
+		"add-me":  "new",
+		"changed": "value",
+	})
+	if err != nil {
+		t.Fatalf("BulkSetVars: %s", err)
+	}
+
+	if stub.setCalls["add-me"] != "new" {
+		t.Errorf("add-me was not set to the real value, got %q", stub.setCalls["add-me"])
+	}
+	if stub.setCalls["changed"] != "value" {
+		t.Errorf("changed was not updated to the new value, got %q", stub.setCalls["changed"])
+	}
+	if _, setKeep := stub.setCalls["keep"]; setKeep {
+		t.Error("unchanged variable \"keep\" should not have been re-set")
+	}
+	if len(stub.deleteCalls) != 1 || stub.deleteCalls[0] != "remove-me" {
+		t.Errorf("got deletes %v, want [\"remove-me\"]", stub.deleteCalls)
+	}
+}
@@ -16,7 +16,7 @@ type RawResponse struct {
 }
 
 // GetRaw will send a get request
-func (p *RawService) GetRaw(path string) (string, *http.Response, error) {
+func (p *RawService) GetRaw(path string, opts ...RequestOption) (string, *http.Response, error) {
 
 	path = strings.TrimPrefix(path, p.client.BaseUrl.String())
 
@@ -27,17 +27,17 @@ func (p *RawService) GetRaw(path string) (string, *http.Response, error) {
 
 	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	response, err := p.client.RawDo(request, nil)
+	response, err := doRawWithOptions(p.client, request, opts...)
 	if err != nil {
 		return "", nil, err
 	}
 
 	if !(response.StatusCode == 200) {
-		return "", nil, &simpleError{fmt.Sprintf("Get returned %d", response.StatusCode)}
+		return "", nil, newAPIError(http.MethodGet, path, response)
 	}
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return "", response, &simpleError{fmt.Sprintf("Read body %s", err)}
+		return "", response, fmt.Errorf("reading response body: %w", err)
 	}
 
 	return string(body), response, nil
@@ -152,52 +152,46 @@ type Change struct {
 }
 
 // LatestResult returns the latest result information for the given plan key
-func (r *ResultService) LatestResult(key string) (*Result, *http.Response, error) {
-	result, resp, err := r.NumberedResult(key + "-latest")
+func (r *ResultService) LatestResult(key string, opts ...RequestOption) (*Result, *http.Response, error) {
+	result, resp, err := r.NumberedResult(key+"-latest", opts...)
 	return result, resp, err
 }
 
 // NumberedResult returns the result information for the given plan key which includes the build number of the desired result
-func (r *ResultService) NumberedResult(key string) (*Result, *http.Response, error) {
+func (r *ResultService) NumberedResult(key string, opts ...RequestOption) (*Result, *http.Response, error) {
 	request, err := r.client.NewRequest(http.MethodGet, numberedResultURL(key), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	result := Result{}
-	response, err := r.client.Do(request, &result)
+	response, err := doWithOptions(r.client, request, &result, opts...)
 	if err != nil {
 		return nil, response, err
 	}
 
 	if response.StatusCode != 200 {
-		return nil, response, &simpleError{fmt.Sprintf("API returned unexpected status code %d", response.StatusCode)}
+		return nil, response, newAPIError(http.MethodGet, numberedResultURL(key), response)
 	}
 
 	return &result, response, err
 }
 
-// ListResults lists the results for a plan
-func (r *ResultService) ListResults(key string) ([]Result, *http.Response, error) {
-	request, err := r.client.NewRequest(http.MethodGet, listResultsURL(key), nil)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	result := ResultsResponse{}
-	response, err := r.client.Do(request, &result)
-	if err != nil {
-		return nil, response, err
-	}
+// ListResults lists the results for a plan. It is a thin wrapper around
+// Iter kept for backward compatibility; prefer Iter for plans with a long
+// build history.
+func (r *ResultService) ListResults(key string, opts ...RequestOption) ([]Result, *http.Response, error) {
+	it := r.Iter(contextFromOptions(opts), key, nil)
 
-	if response.StatusCode != 200 {
-		return nil, response, &simpleError{fmt.Sprintf("API returned unexpected status code %d", response.StatusCode)}
+	var results []Result
+	for it.Next() {
+		results = append(results, *it.Result())
 	}
 
-	return result.Results.Result, response, err
+	return results, it.resp, it.Err()
 }
 
-func (r *ResultService) GetExpanded(key string, expand []string) (*Result, *http.Response, error) {
+func (r *ResultService) GetExpanded(key string, expand []string, opts ...RequestOption) (*Result, *http.Response, error) {
 
 	pathStr := fmt.Sprintf("result/%s", key)
 	request, err := r.client.NewRequest(http.MethodGet, pathStr, nil)
@@ -212,20 +206,20 @@ func (r *ResultService) GetExpanded(key string, expand []string) (*Result, *http
 	request.URL.RawQuery = q.Encode()
 
 	result := Result{}
-	response, err := r.client.Do(request, &result)
+	response, err := doWithOptions(r.client, request, &result, opts...)
 	if err != nil {
 		return nil, response, err
 	}
 
 	if response.StatusCode != 200 {
-		return nil, response, &simpleError{fmt.Sprintf("API returned unexpected status code %d", response.StatusCode)}
+		return nil, response, newAPIError(http.MethodGet, pathStr, response)
 	}
 
 	return &result, response, err
 
 }
 
-func (r *ResultService) GetLatestExpanded(key string, expand []string) (*Result, *http.Response, error) {
+func (r *ResultService) GetLatestExpanded(key string, expand []string, opts ...RequestOption) (*Result, *http.Response, error) {
 
 	pathStr := fmt.Sprintf("result/%s-latest", key)
 	request, err := r.client.NewRequest(http.MethodGet, pathStr, nil)
@@ -240,13 +234,13 @@ func (r *ResultService) GetLatestExpanded(key string, expand []string) (*Result,
 	request.URL.RawQuery = q.Encode()
 
 	result := Result{}
-	response, err := r.client.Do(request, &result)
+	response, err := doWithOptions(r.client, request, &result, opts...)
 	if err != nil {
 		return nil, response, err
 	}
 
 	if response.StatusCode != 200 {
-		return nil, response, &simpleError{fmt.Sprintf("API returned unexpected status code %d", response.StatusCode)}
+		return nil, response, newAPIError(http.MethodGet, pathStr, response)
 	}
 
 	return &result, response, err
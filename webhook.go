@@ -0,0 +1,213 @@
+package bamboo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// nonceTTL is how long a webhook nonce is remembered for replay protection
+const nonceTTL = 5 * time.Minute
+
+// WebhookEvent identifies the kind of notification Bamboo sent
+type WebhookEvent string
+
+const (
+	WebhookEventBuildCompleted WebhookEvent = "BUILD_COMPLETED"
+	WebhookEventBuildFailed    WebhookEvent = "BUILD_FAILED"
+	WebhookEventCommentAdded   WebhookEvent = "COMMENT_ADDED"
+)
+
+// WebhookPayload is the envelope Bamboo posts to a registered webhook
+type WebhookPayload struct {
+	Event   WebhookEvent `json:"event"`
+	Nonce   string       `json:"nonce"`
+	Result  *Result      `json:"result,omitempty"`
+	Comment *Comment     `json:"comment,omitempty"`
+}
+
+// WebhookServer decodes Bamboo build-notification webhooks and dispatches
+// them to user-registered callbacks. It implements http.Handler so it can
+// be mounted directly on any Go HTTP server.
+type WebhookServer struct {
+	// Secret is used to verify the HMAC signature Bamboo sends with every
+	// notification. If empty, signature verification is skipped.
+	Secret string
+
+	mu              sync.Mutex
+	seenNonces      map[string]time.Time
+	onBuildComplete []func(*Result)
+	onBuildFailed   []func(*Result)
+	onCommentAdded  []func(*Comment)
+}
+
+// NewWebhookServer returns a WebhookServer that verifies incoming
+// notifications against secret. Pass an empty string to disable
+// signature verification, e.g. for local testing.
+func NewWebhookServer(secret string) *WebhookServer {
+	return &WebhookServer{
+		Secret:     secret,
+		seenNonces: make(map[string]time.Time),
+	}
+}
+
+// OnBuildCompleted registers a callback invoked for every BUILD_COMPLETED notification
+func (w *WebhookServer) OnBuildCompleted(fn func(*Result)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onBuildComplete = append(w.onBuildComplete, fn)
+}
+
+// OnBuildFailed registers a callback invoked for every BUILD_FAILED notification
+func (w *WebhookServer) OnBuildFailed(fn func(*Result)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onBuildFailed = append(w.onBuildFailed, fn)
+}
+
+// OnCommentAdded registers a callback invoked for every COMMENT_ADDED notification
+func (w *WebhookServer) OnCommentAdded(fn func(*Comment)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onCommentAdded = append(w.onCommentAdded, fn)
+}
+
+// ServeHTTP verifies the request signature, rejects replayed nonces, decodes
+// the notification payload and dispatches it to the matching callbacks
+func (w *WebhookServer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	body, err := readAndVerify(req, w.Secret)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(rw, fmt.Sprintf("decoding webhook payload: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if payload.Nonce != "" && !w.checkAndRememberNonce(payload.Nonce) {
+		http.Error(rw, "nonce already used", http.StatusConflict)
+		return
+	}
+
+	w.dispatch(payload)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// dispatch invokes the callbacks registered for payload.Event. The callback
+// slice is copied while w.mu is held and then invoked after releasing it, so
+// a callback that registers another callback (or that blocks) can't
+// deadlock against OnBuildCompleted/OnBuildFailed/OnCommentAdded or
+// serialize unrelated ServeHTTP calls behind it. Each callback runs under
+// its own recover so one panicking handler can't take down the others or
+// the HTTP handler goroutine.
+func (w *WebhookServer) dispatch(payload WebhookPayload) {
+	switch payload.Event {
+	case WebhookEventBuildCompleted:
+		w.mu.Lock()
+		fns := append([]func(*Result){}, w.onBuildComplete...)
+		w.mu.Unlock()
+		for _, fn := range fns {
+			callResultFunc(fn, payload.Result)
+		}
+	case WebhookEventBuildFailed:
+		w.mu.Lock()
+		fns := append([]func(*Result){}, w.onBuildFailed...)
+		w.mu.Unlock()
+		for _, fn := range fns {
+			callResultFunc(fn, payload.Result)
+		}
+	case WebhookEventCommentAdded:
+		w.mu.Lock()
+		fns := append([]func(*Comment){}, w.onCommentAdded...)
+		w.mu.Unlock()
+		for _, fn := range fns {
+			callCommentFunc(fn, payload.Comment)
+		}
+	}
+}
+
+func callResultFunc(fn func(*Result), result *Result) {
+	defer func() { recover() }()
+	fn(result)
+}
+
+func callCommentFunc(fn func(*Comment), comment *Comment) {
+	defer func() { recover() }()
+	fn(comment)
+}
+
+// checkAndRememberNonce returns false if nonce has already been seen within
+// nonceTTL, and purges expired entries while it's at it
+func (w *WebhookServer) checkAndRememberNonce(nonce string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	for n, seenAt := range w.seenNonces {
+		if now.Sub(seenAt) > nonceTTL {
+			delete(w.seenNonces, n)
+		}
+	}
+
+	if _, ok := w.seenNonces[nonce]; ok {
+		return false
+	}
+	w.seenNonces[nonce] = now
+	return true
+}
+
+// readAndVerify reads the request body and, if secret is non-empty, verifies
+// the X-Bamboo-Webhook-Signature header against the HMAC-SHA256 of the body
+func readAndVerify(req *http.Request, secret string) ([]byte, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading webhook body: %w", err)
+	}
+
+	if secret == "" {
+		return body, nil
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := fmt.Sprintf("%x", mac.Sum(nil))
+
+	signature := req.Header.Get("X-Bamboo-Webhook-Signature")
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, fmt.Errorf("webhook signature mismatch")
+	}
+
+	return body, nil
+}
+
+// RegisterWithBamboo installs callbackURL as a webhook endpoint on the
+// Bamboo server so that it starts POSTing build notifications to it
+func RegisterWithBamboo(client *Client, callbackURL string) error {
+	body := struct {
+		URL string `json:"url"`
+	}{URL: callbackURL}
+
+	request, err := client.NewRequest(http.MethodPost, "webhook.json", body)
+	if err != nil {
+		return err
+	}
+
+	response, err := doJSON(client, request, nil)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusOK {
+		return newAPIError(http.MethodPost, "webhook.json", response)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package bamboo_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bamboo "github.com/stefan-kiss/go-bamboo"
+)
+
+func postWebhook(t *testing.T, ts *httptest.Server, secret string, payload bamboo.WebhookPayload) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshaling payload: %s", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("building request: %s", err)
+	}
+
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set("X-Bamboo-Webhook-Signature", fmt.Sprintf("%x", mac.Sum(nil)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("posting webhook: %s", err)
+	}
+	return resp
+}
+
+func TestWebhookServerDispatchesBuildCompleted(t *testing.T) {
+	server := bamboo.NewWebhookServer("s3cr3t")
+
+	var got *bamboo.Result
+	server.OnBuildCompleted(func(r *bamboo.Result) { got = r })
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	resp := postWebhook(t, ts, "s3cr3t", bamboo.WebhookPayload{
+		Event:  bamboo.WebhookEventBuildCompleted,
+		Nonce:  "nonce-1",
+		Result: &bamboo.Result{Key: "PROJ-PLAN-1"},
+	})
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("got status %d, want 204", resp.StatusCode)
+	}
+	if got == nil || got.Key != "PROJ-PLAN-1" {
+		t.Errorf("OnBuildCompleted callback was not invoked with the expected result, got %+v", got)
+	}
+}
+
+func TestWebhookServerRejectsBadSignature(t *testing.T) {
+	server := bamboo.NewWebhookServer("s3cr3t")
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	resp := postWebhook(t, ts, "wrong-secret", bamboo.WebhookPayload{
+		Event: bamboo.WebhookEventBuildCompleted,
+		Nonce: "nonce-2",
+	})
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got status %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestWebhookServerRejectsReplayedNonce(t *testing.T) {
+	server := bamboo.NewWebhookServer("")
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	payload := bamboo.WebhookPayload{Event: bamboo.WebhookEventBuildCompleted, Nonce: "replay-me"}
+
+	first := postWebhook(t, ts, "", payload)
+	if first.StatusCode != http.StatusNoContent {
+		t.Fatalf("first delivery: got status %d, want 204", first.StatusCode)
+	}
+
+	second := postWebhook(t, ts, "", payload)
+	if second.StatusCode != http.StatusConflict {
+		t.Errorf("replayed delivery: got status %d, want 409", second.StatusCode)
+	}
+}